@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "fmt"
+
+// Rule IDs produced by validateTerraform. Keeping them as constants gives
+// reporters (and anyone gating CI on a specific rule) a stable identifier
+// that doesn't depend on the wording of a message.
+const (
+	RuleInvalidPostureID           = "AGF001"
+	RuleInvalidPolicySetID         = "AGF002"
+	RuleInvalidPolicyID            = "AGF003"
+	RuleInvalidPostureDeploymentID = "AGF004"
+	RuleInvalidParent              = "AGF005"
+	RuleDanglingPostureReference   = "AGF006"
+	RuleStructural                 = "AGF007"
+	RuleParseError                 = "AGF008"
+)
+
+// Finding is a single validation violation. It carries enough structure
+// for a reporter to render it as a one-line message, a JSON record, or a
+// SARIF result, without re-deriving any of that from a formatted string.
+type Finding struct {
+	RuleID   string `json:"ruleId"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// String renders a Finding as a single human-readable line for the text
+// reporter. This is a new format (severity, stable RuleID, message, then
+// file:line) rather than the prior free-form error strings; anything that
+// scraped the old "Error: Invalid '...' value ..." text needs updating.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: [%s] %s (%s:%d)", f.Severity, f.RuleID, f.Message, f.File, f.Line)
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// PatternRule describes a single named check applied to an HCL attribute
+// value: its format, optional length bounds, and how loudly to report a
+// violation.
+type PatternRule struct {
+	Name      string `yaml:"name" json:"name"`
+	Attribute string `yaml:"attribute" json:"attribute"`
+	Pattern   string `yaml:"pattern" json:"pattern"`
+	MinLength int    `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength int    `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	Severity  string `yaml:"severity,omitempty" json:"severity,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+// PatternPolicy is the top-level shape of a policy file: a named set of
+// PatternRules that validateRegex applies to every .tf file it scans.
+type PatternPolicy struct {
+	Name  string        `yaml:"name" json:"name"`
+	Rules []PatternRule `yaml:"rules" json:"rules"`
+}
+
+// defaultPatternPolicy mirrors the three ID formats validateTerraform has
+// always enforced, re-expressed as the built-in policy used when the
+// caller doesn't pass --policy.
+func defaultPatternPolicy() *PatternPolicy {
+	policy := &PatternPolicy{
+		Name: "default",
+		Rules: []PatternRule{
+			{Name: "AGF001", Attribute: "posture_id", Pattern: `^[a-z][a-z0-9-_]{0,62}$`, Severity: "ERROR"},
+			{Name: "AGF002", Attribute: "policy_set_id", Pattern: `^[a-z][a-z0-9-_]{0,62}$`, Severity: "ERROR"},
+			{Name: "AGF003", Attribute: "policy_id", Pattern: `^[a-zA-Z][a-zA-Z0-9-_]{0,62}$`, Severity: "ERROR"},
+		},
+	}
+	// The default policy is trusted input; a compile failure here is a
+	// programmer error, not a user-facing one.
+	if err := policy.compile(); err != nil {
+		panic(fmt.Sprintf("cmd: default pattern policy failed to compile: %v", err))
+	}
+	return policy
+}
+
+// compile pre-compiles every rule's regex pattern, failing fast on a
+// malformed policy file rather than erroring partway through a scan.
+func (p *PatternPolicy) compile() error {
+	for i := range p.Rules {
+		re, err := regexp.Compile(p.Rules[i].Pattern)
+		if err != nil {
+			return fmt.Errorf("rule %q: invalid pattern %q: %w", p.Rules[i].Name, p.Rules[i].Pattern, err)
+		}
+		p.Rules[i].compiled = re
+		if p.Rules[i].Severity == "" {
+			p.Rules[i].Severity = "ERROR"
+		}
+	}
+	return nil
+}
+
+// LoadPolicy resolves ref to a PatternPolicy. An empty ref returns the
+// built-in default policy. Otherwise ref is interpreted as:
+//   - "file://path" or a bare filesystem path
+//   - "http://" or "https://" URL
+//   - "oci://registry/repository:tag", an OCI artifact reference
+//
+// The loaded document may be YAML or JSON; both unmarshal through the
+// same yaml.v3 decoder since JSON is a subset of YAML.
+func LoadPolicy(ref string) (*PatternPolicy, error) {
+	if ref == "" {
+		return defaultPatternPolicy(), nil
+	}
+
+	raw, err := fetchPolicyBytes(ref)
+	if err != nil {
+		return nil, fmt.Errorf("loading policy %q: %w", ref, err)
+	}
+
+	var policy PatternPolicy
+	if err := yaml.Unmarshal(raw, &policy); err != nil {
+		return nil, fmt.Errorf("parsing policy %q: %w", ref, err)
+	}
+	if len(policy.Rules) == 0 {
+		return nil, fmt.Errorf("policy %q declares no rules", ref)
+	}
+	if err := policy.compile(); err != nil {
+		return nil, fmt.Errorf("policy %q: %w", ref, err)
+	}
+	return &policy, nil
+}
+
+// fetchPolicyBytes retrieves the raw policy document behind ref, dispatching
+// on its scheme.
+func fetchPolicyBytes(ref string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(ref, "file://"):
+		return os.ReadFile(strings.TrimPrefix(ref, "file://"))
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"):
+		return fetchPolicyHTTP(ref)
+	case strings.HasPrefix(ref, "oci://"):
+		return fetchPolicyOCI(strings.TrimPrefix(ref, "oci://"))
+	default:
+		return os.ReadFile(ref)
+	}
+}
+
+// fetchPolicyHTTP downloads a policy document over plain HTTP(S).
+func fetchPolicyHTTP(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// fetchPolicyOCI pulls the single-layer policy artifact referenced by
+// ociRef (e.g. "gcr.io/my-project/posture-policy:v1") and returns its
+// layer content, using the local Go module cache's OCI client rather than
+// shelling out to an external tool.
+func fetchPolicyOCI(ociRef string) ([]byte, error) {
+	repo, err := remote.NewRepository(ociRef)
+	if err != nil {
+		return nil, fmt.Errorf("resolving OCI reference: %w", err)
+	}
+
+	ctx := context.Background()
+	store := memory.New()
+	desc, err := oras.Copy(ctx, repo, repo.Reference.Reference, store, repo.Reference.Reference, oras.DefaultCopyOptions)
+	if err != nil {
+		return nil, fmt.Errorf("pulling OCI artifact: %w", err)
+	}
+
+	// desc is the artifact's root manifest; the policy document is its layer.
+	manifestBytes, err := content.FetchAll(ctx, store, desc)
+	if err != nil {
+		return nil, fmt.Errorf("reading OCI manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing OCI manifest: %w", err)
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("OCI artifact has %d layers, want exactly one", len(manifest.Layers))
+	}
+
+	rc, err := store.Fetch(ctx, manifest.Layers[0])
+	if err != nil {
+		return nil, fmt.Errorf("reading OCI artifact: %w", err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
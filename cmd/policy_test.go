@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicyEmptyRefReturnsDefault(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy(\"\") = %v", err)
+	}
+	if policy.Name != "default" || len(policy.Rules) != 3 {
+		t.Errorf("LoadPolicy(\"\") = %+v, want the built-in 3-rule default policy", policy)
+	}
+}
+
+func TestLoadPolicyFromBarePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte(`
+name: custom
+rules:
+  - name: CUSTOM001
+    attribute: posture_id
+    pattern: '^p-[0-9]+$'
+`), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy(%s) = %v", path, err)
+	}
+	if policy.Name != "custom" || len(policy.Rules) != 1 || policy.Rules[0].Name != "CUSTOM001" {
+		t.Errorf("LoadPolicy(%s) = %+v, want the custom policy just written", path, policy)
+	}
+}
+
+func TestLoadPolicyFromFileURI(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte(`
+name: custom
+rules:
+  - name: CUSTOM001
+    attribute: posture_id
+    pattern: '^p-[0-9]+$'
+`), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	policy, err := LoadPolicy("file://" + path)
+	if err != nil {
+		t.Fatalf("LoadPolicy(file://%s) = %v", path, err)
+	}
+	if policy.Name != "custom" {
+		t.Errorf("LoadPolicy(file://%s).Name = %q, want %q", path, policy.Name, "custom")
+	}
+}
+
+func TestLoadPolicyFromHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`
+name: remote
+rules:
+  - name: REMOTE001
+    attribute: posture_id
+    pattern: '^p-[0-9]+$'
+`))
+	}))
+	defer server.Close()
+
+	policy, err := LoadPolicy(server.URL)
+	if err != nil {
+		t.Fatalf("LoadPolicy(%s) = %v", server.URL, err)
+	}
+	if policy.Name != "remote" {
+		t.Errorf("LoadPolicy(%s).Name = %q, want %q", server.URL, policy.Name, "remote")
+	}
+}
+
+func TestLoadPolicyHTTPErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := LoadPolicy(server.URL); err == nil {
+		t.Errorf("LoadPolicy(%s) = nil error, want an error for a non-200 response", server.URL)
+	}
+}
+
+func TestLoadPolicyMissingFileIsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.yaml")
+	if _, err := LoadPolicy(path); err == nil {
+		t.Errorf("LoadPolicy(%s) = nil error, want an error for a missing file", path)
+	}
+}
+
+func TestLoadPolicyNoRulesIsError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(path, []byte("name: empty\nrules: []\n"), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	if _, err := LoadPolicy(path); err == nil {
+		t.Errorf("LoadPolicy(%s) = nil error, want an error: a policy with no rules is useless", path)
+	}
+}
+
+func TestLoadPolicyInvalidPatternIsError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(path, []byte(`
+name: bad
+rules:
+  - name: BAD001
+    attribute: posture_id
+    pattern: '['
+`), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	if _, err := LoadPolicy(path); err == nil {
+		t.Errorf("LoadPolicy(%s) = nil error, want an error: pattern '[' doesn't compile as a regex", path)
+	}
+}
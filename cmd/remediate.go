@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AppliedFix describes a single attribute value rewritten by remediation.
+type AppliedFix struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Attribute string `json:"attribute"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+}
+
+// RemediationSummary is what remediateTerraform returns so callers (the
+// CLI or a future library consumer) can render a report without
+// re-parsing anything.
+type RemediationSummary struct {
+	FilesScanned      int          `json:"filesScanned"`
+	FilesFixed        int          `json:"filesFixed"`
+	FilesSkipped      int          `json:"filesSkipped"`
+	FilesStillFailing int          `json:"filesStillFailing"`
+	Fixes             []AppliedFix `json:"fixes"`
+}
+
+// disallowedCharsRegex matches anything outside the alphanumeric/dash/
+// underscore alphabet the Google provider accepts for posture_id and
+// policy_set_id.
+var disallowedCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// normalizeLowerID mechanically repairs a posture_id/policy_set_id style
+// value: lowercases it, replaces disallowed characters with "_", strips
+// leading characters that can't start the identifier, and truncates to
+// the 63 character limit.
+func normalizeLowerID(value string) string {
+	normalized := strings.ToLower(value)
+	normalized = disallowedCharsRegex.ReplaceAllString(normalized, "_")
+	normalized = strings.TrimLeft(normalized, "0123456789-_")
+	if len(normalized) > 63 {
+		normalized = normalized[:63]
+	}
+	return normalized
+}
+
+// remediateTerraform walks terraformDir looking for the same three ID
+// attributes validateTerraform checks and, where a value is invalid but
+// can be mechanically normalized, rewrites it in place using hclwrite so
+// formatting and comments are preserved. When dryRun is true, no files are
+// written; the summary still reports what would have changed.
+func remediateTerraform(terraformDir string, dryRun bool) (*RemediationSummary, error) {
+	summary := &RemediationSummary{}
+
+	walkErr := filepath.Walk(terraformDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".tf") {
+			return nil
+		}
+		summary.FilesScanned++
+
+		original, readErr := os.ReadFile(path)
+		if readErr != nil {
+			summary.FilesSkipped++
+			return nil
+		}
+
+		hclFile, diags := hclwrite.ParseConfig(original, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			summary.FilesSkipped++
+			return nil
+		}
+
+		fixes, fileChanged, stillFailing := remediateFile(path, original, hclFile)
+		if stillFailing {
+			summary.FilesStillFailing++
+		}
+		if len(fixes) == 0 {
+			return nil
+		}
+
+		summary.Fixes = append(summary.Fixes, fixes...)
+		if fileChanged {
+			summary.FilesFixed++
+			if !dryRun {
+				if writeErr := os.WriteFile(path, hclFile.Bytes(), info.Mode()); writeErr != nil {
+					return fmt.Errorf("writing fixed file %s: %w", path, writeErr)
+				}
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return summary, fmt.Errorf("walking %s: %w", terraformDir, walkErr)
+	}
+	return summary, nil
+}
+
+// remediateFile applies normalization to every posture_id, policy_set_id,
+// and policy_id attribute found in a google_securityposture_posture
+// resource in hclFile, mutating the attribute tokens in place. It reports
+// the fixes applied and whether any invalid value could not be repaired
+// mechanically.
+func remediateFile(path string, original []byte, hclFile *hclwrite.File) ([]AppliedFix, bool, bool) {
+	var fixes []AppliedFix
+	changed := false
+	stillFailing := false
+
+	for _, block := range hclFile.Body().Blocks() {
+		if block.Type() != "resource" || len(block.Labels()) == 0 || block.Labels()[0] != posturePostureResourceType {
+			continue
+		}
+		remediateBody(path, original, block.Body(), &fixes, &changed, &stillFailing)
+	}
+	return fixes, changed, stillFailing
+}
+
+// policyIDNormalizer mirrors normalizeLowerID but keeps the original
+// case, since policy_id (unlike posture_id/policy_set_id) allows mixed
+// case values.
+func policyIDNormalizer(value string) string {
+	normalized := disallowedCharsRegex.ReplaceAllString(value, "_")
+	normalized = strings.TrimLeft(normalized, "0123456789-_")
+	if len(normalized) > 63 {
+		normalized = normalized[:63]
+	}
+	return normalized
+}
+
+// remediateBody recurses through a resource body and its nested blocks,
+// fixing posture_id/policy_set_id/policy_id attributes wherever found.
+func remediateBody(path string, original []byte, body *hclwrite.Body, fixes *[]AppliedFix, changed, stillFailing *bool) {
+	remediateAttribute(path, original, body, "posture_id", postureIDFormatValidation, normalizeLowerID, fixes, changed, stillFailing)
+	remediateAttribute(path, original, body, "policy_set_id", policySetIDFormatValidation, normalizeLowerID, fixes, changed, stillFailing)
+	remediateAttribute(path, original, body, "policy_id", policyIDFormatValidation, policyIDNormalizer, fixes, changed, stillFailing)
+
+	for _, block := range body.Blocks() {
+		remediateBody(path, original, block.Body(), fixes, changed, stillFailing)
+	}
+}
+
+// remediateAttribute fixes a single named attribute in body if it's
+// present, invalid, and normalizer produces a value format accepts.
+func remediateAttribute(path string, original []byte, body *hclwrite.Body, name string, format *regexp.Regexp, normalizer func(string) string, fixes *[]AppliedFix, changed, stillFailing *bool) {
+	attr := body.GetAttribute(name)
+	if attr == nil {
+		return
+	}
+
+	before, ok := attributeStringValue(attr)
+	if !ok || format.MatchString(before) {
+		return
+	}
+
+	after := normalizer(before)
+	line := findAttributeLine(original, name, before)
+
+	if !format.MatchString(after) {
+		*stillFailing = true
+		return
+	}
+
+	body.SetAttributeValue(name, cty.StringVal(after))
+	*changed = true
+	*fixes = append(*fixes, AppliedFix{Path: path, Line: line, Attribute: name, Before: before, After: after})
+}
+
+// findAttributeLine locates the 1-based line of the `name = "value"`
+// assignment in original. hclwrite's token stream doesn't retain source
+// positions once mutated, so the diff-style summary looks the value up
+// in the untouched bytes read before remediation began.
+func findAttributeLine(original []byte, name, value string) int {
+	scanner := bufio.NewScanner(strings.NewReader(string(original)))
+	lineNum := 1
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, name) && strings.Contains(line, value) {
+			return lineNum
+		}
+		lineNum++
+	}
+	return -1
+}
+
+// attributeStringValue extracts the literal string value of a hclwrite
+// attribute by inspecting its token stream, since hclwrite operates on
+// tokens rather than evaluated values. It only recognizes a plain quoted
+// literal (`"foo"`) with no template interpolation: a value like
+// `"${var.prefix}-POSTURE"` is reported as not-a-literal so callers don't
+// mistake an interpolated expression for a fixable static string and
+// clobber the variable reference.
+func attributeStringValue(attr *hclwrite.Attribute) (string, bool) {
+	tokens := attr.Expr().BuildTokens(nil)
+	if len(tokens) == 2 && tokens[0].Type == hclsyntax.TokenOQuote && tokens[1].Type == hclsyntax.TokenCQuote {
+		return "", true
+	}
+	if len(tokens) != 3 || tokens[0].Type != hclsyntax.TokenOQuote || tokens[2].Type != hclsyntax.TokenCQuote {
+		return "", false
+	}
+	if tokens[1].Type != hclsyntax.TokenQuotedLit {
+		return "", false
+	}
+	return string(tokens[1].Bytes), true
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+func TestRemediateTerraformUnfixableFileStillCounted(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "my-posture"
+
+  policy_sets {
+    policy_set_id = "my-policy-set"
+
+    policies {
+      policy_id = "---"
+    }
+  }
+}
+`)
+
+	summary, err := remediateTerraform(dir, true)
+	if err != nil {
+		t.Fatalf("remediateTerraform(%s, true) = %v", dir, err)
+	}
+
+	if summary.FilesStillFailing != 1 {
+		t.Errorf("remediateTerraform(%s, true).FilesStillFailing = %d, want 1: an unfixable policy_id must still be counted even though no fix was applied", dir, summary.FilesStillFailing)
+	}
+}
+
+func TestRemediateFileSkipsInterpolatedValues(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte(`
+resource "google_securityposture_posture" "example" {
+  posture_id = "${var.prefix}-POSTURE"
+}
+`)
+	path := writeTerraformFile(t, dir, "posture.tf", string(original))
+
+	hclFile, diags := hclwrite.ParseConfig(original, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("parsing %s: %s", path, diags.Error())
+	}
+
+	fixes, changed, _ := remediateFile(path, original, hclFile)
+
+	if len(fixes) != 0 || changed {
+		t.Errorf("remediateFile(%s) = (%v, %v), want no fixes: an interpolated posture_id must not be flattened into a static literal", path, fixes, changed)
+	}
+	if got := string(hclFile.Bytes()); got != string(original) {
+		t.Errorf("remediateFile(%s) rewrote the file despite reporting no changes:\ngot:\n%s\nwant (unchanged):\n%s", path, got, original)
+	}
+}
+
+func TestRemediateFileIgnoresNonPostureResources(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte(`
+resource "google_storage_bucket" "example" {
+  posture_id = "NOT-A-POSTURE-RESOURCE"
+}
+`)
+	path := writeTerraformFile(t, dir, "other.tf", string(original))
+
+	hclFile, diags := hclwrite.ParseConfig(original, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("parsing %s: %s", path, diags.Error())
+	}
+
+	fixes, changed, stillFailing := remediateFile(path, original, hclFile)
+
+	if len(fixes) != 0 || changed || stillFailing {
+		t.Errorf("remediateFile(%s) = (%v, %v, %v), want no fixes: posture_id on a non-posture resource must not be rewritten", path, fixes, changed, stillFailing)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Reporter renders a set of Findings into a byte stream in some output
+// format. validateTerraform itself stays format-agnostic; the CLI picks a
+// Reporter based on --output.
+type Reporter interface {
+	Report(findings []Finding) ([]byte, error)
+}
+
+// NewReporter resolves the name passed to --output to a Reporter
+// implementation. It returns an error for anything else so an unknown
+// --output value fails fast instead of silently falling back to text.
+func NewReporter(name string) (Reporter, error) {
+	switch name {
+	case "", "text":
+		return textReporter{}, nil
+	case "json":
+		return jsonReporter{}, nil
+	case "sarif":
+		return sarifReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want one of: text, json, sarif", name)
+	}
+}
+
+// textReporter renders one line per finding via Finding.String, for
+// terminal output. It supersedes validateTerraform's old free-form error
+// strings; the shape is structured now, not byte-for-byte compatible.
+type textReporter struct{}
+
+func (textReporter) Report(findings []Finding) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, finding := range findings {
+		buf.WriteString(finding.String())
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonReporter renders findings as a JSON array, suitable for feeding
+// into another tool or a dashboard.
+type jsonReporter struct{}
+
+func (jsonReporter) Report(findings []Finding) ([]byte, error) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return json.MarshalIndent(findings, "", "  ")
+}
+
+// sarifReporter renders findings as a SARIF 2.1.0 log, so results can be
+// uploaded to GitHub code scanning (or any other SARIF consumer).
+type sarifReporter struct{}
+
+// sarifLog, sarifRun, sarifTool, sarifDriver, sarifRule, sarifResult, etc.
+// are a minimal subset of the SARIF 2.1.0 object model -- just enough to
+// carry a rule catalog and a list of results with file/line locations.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	ShortDescription sarifMultiformatString `json:"shortDescription"`
+}
+
+type sarifMultiformatString struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string                 `json:"ruleId"`
+	Level     string                 `json:"level"`
+	Message   sarifMultiformatString `json:"message"`
+	Locations []sarifLocation        `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevel maps a Finding's severity to the level values SARIF expects.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "WARNING":
+		return "warning"
+	case "NOTE":
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+func (sarifReporter) Report(findings []Finding) ([]byte, error) {
+	rulesSeen := map[string]bool{}
+	var rules []sarifRule
+	results := make([]sarifResult, 0, len(findings))
+
+	for _, finding := range findings {
+		if !rulesSeen[finding.RuleID] {
+			rulesSeen[finding.RuleID] = true
+			rules = append(rules, sarifRule{
+				ID:               finding.RuleID,
+				ShortDescription: sarifMultiformatString{Text: finding.Message},
+			})
+		}
+		results = append(results, sarifResult{
+			RuleID:  finding.RuleID,
+			Level:   sarifLevel(finding.Severity),
+			Message: sarifMultiformatString{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: finding.File},
+					Region:           sarifRegion{StartLine: finding.Line, StartColumn: finding.Column},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "agf",
+				InformationURI: "https://github.com/GoogleCloudPlatform/adaptive-governance-framework",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewReporterUnknownFormatIsError(t *testing.T) {
+	if _, err := NewReporter("yaml"); err == nil {
+		t.Errorf(`NewReporter("yaml") = nil error, want an error for an unsupported format`)
+	}
+}
+
+func TestNewReporterKnownFormats(t *testing.T) {
+	for _, name := range []string{"", "text", "json", "sarif"} {
+		if _, err := NewReporter(name); err != nil {
+			t.Errorf("NewReporter(%q) = %v, want no error", name, err)
+		}
+	}
+}
+
+func testFindings() []Finding {
+	return []Finding{
+		{RuleID: RuleInvalidPostureID, Severity: "ERROR", File: "posture.tf", Line: 3, Column: 5, Message: "Invalid 'posture_id' value 'BAD'.", Expected: "^[a-z]...$", Actual: "BAD"},
+	}
+}
+
+func TestTextReporterRendersOneLinePerFinding(t *testing.T) {
+	reporter, _ := NewReporter("text")
+	out, err := reporter.Report(testFindings())
+	if err != nil {
+		t.Fatalf("textReporter.Report(...) = %v", err)
+	}
+
+	got := strings.TrimRight(string(out), "\n")
+	want := "ERROR: [AGF001] Invalid 'posture_id' value 'BAD'. (posture.tf:3)"
+	if got != want {
+		t.Errorf("textReporter.Report(...) = %q, want %q", got, want)
+	}
+}
+
+func TestJSONReporterRoundTrips(t *testing.T) {
+	reporter, _ := NewReporter("json")
+	out, err := reporter.Report(testFindings())
+	if err != nil {
+		t.Fatalf("jsonReporter.Report(...) = %v", err)
+	}
+
+	var findings []Finding
+	if err := json.Unmarshal(out, &findings); err != nil {
+		t.Fatalf("unmarshaling jsonReporter output: %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != RuleInvalidPostureID {
+		t.Errorf("jsonReporter.Report(...) round-tripped to %+v, want the original finding back", findings)
+	}
+}
+
+func TestJSONReporterEmptyFindingsIsEmptyArray(t *testing.T) {
+	reporter, _ := NewReporter("json")
+	out, err := reporter.Report(nil)
+	if err != nil {
+		t.Fatalf("jsonReporter.Report(nil) = %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "[]" {
+		t.Errorf("jsonReporter.Report(nil) = %s, want the literal JSON array \"[]\" rather than \"null\"", out)
+	}
+}
+
+func TestSarifReporterProducesValidLog(t *testing.T) {
+	reporter, _ := NewReporter("sarif")
+	out, err := reporter.Report(testFindings())
+	if err != nil {
+		t.Fatalf("sarifReporter.Report(...) = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatalf("unmarshaling sarifReporter output: %v", err)
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("sarifReporter.Report(...).Version = %q, want %q", log.Version, "2.1.0")
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("sarifReporter.Report(...).Runs = %v, want exactly one run", log.Runs)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 1 || run.Results[0].RuleID != RuleInvalidPostureID {
+		t.Errorf("sarifReporter.Report(...).Runs[0].Results = %+v, want one result for %s", run.Results, RuleInvalidPostureID)
+	}
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != RuleInvalidPostureID {
+		t.Errorf("sarifReporter.Report(...).Runs[0].Tool.Driver.Rules = %+v, want a deduplicated rule catalog entry for %s", run.Tool.Driver.Rules, RuleInvalidPostureID)
+	}
+}
+
+func TestSarifLevelMapsSeverity(t *testing.T) {
+	cases := map[string]string{
+		"WARNING": "warning",
+		"NOTE":    "note",
+		"ERROR":   "error",
+		"":        "error",
+	}
+	for severity, want := range cases {
+		if got := sarifLevel(severity); got != want {
+			t.Errorf("sarifLevel(%q) = %q, want %q", severity, got, want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/cobra"
+)
+
+// validateRegex walks terraformDir and applies every rule in policy to the
+// matching attribute wherever it's assigned in a .tf file, regardless of
+// which resource or block it's nested in. It's the policy-driven
+// counterpart to validateTerraform's hardcoded checks.
+//
+// Only native HCL syntax is supported: findAttributesByName walks the raw
+// hclsyntax tree to find an attribute by name regardless of nesting, and
+// hclsyntax has no .tf.json equivalent that exposes its block structure
+// generically without a fixed schema (unlike validateTerraform, which
+// always knows the exact resource schema it's looking for). A .tf.json
+// file is reported, not silently skipped, so a directory mixing both
+// formats doesn't look fully checked when it isn't.
+func validateRegex(terraformDir string, policy *PatternPolicy) []string {
+	var errorMessages []string
+	parser := hclparse.NewParser()
+
+	err := filepath.Walk(terraformDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		lowerName := strings.ToLower(info.Name())
+		if strings.HasSuffix(lowerName, ".tf.json") {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error: %s: regx does not support .tf.json files yet; skipping.", path))
+			return nil
+		}
+		if !strings.HasSuffix(lowerName, ".tf") {
+			return nil
+		}
+
+		terraformCode, readErr := os.ReadFile(path)
+		if readErr != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error: Could not read Terraform file: %s. Details: %v", path, readErr))
+			return nil
+		}
+
+		file, diags := parser.ParseHCL(terraformCode, path)
+		if diags.HasErrors() {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error: Could not parse Terraform file: %s. Details: %s", path, diags.Error()))
+			return nil
+		}
+
+		for _, rule := range policy.Rules {
+			for _, attr := range findAttributesByName(file.Body, rule.Attribute) {
+				errorMessages = append(errorMessages, validateAgainstRule(path, attr, rule)...)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		errorMessages = append(errorMessages, fmt.Sprintf("Error: Could not walk the directory: %v", err))
+	}
+
+	return errorMessages
+}
+
+// findAttributesByName recursively collects every attribute named name
+// anywhere in body, including inside nested blocks. Policy rules target
+// an attribute name rather than a specific resource schema, so this walks
+// the raw hclsyntax tree instead of going through a fixed BodySchema.
+// validateRegex only ever calls this with a body parsed via ParseHCL, so
+// the type assertion always succeeds.
+func findAttributesByName(body hcl.Body, name string) []*hcl.Attribute {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var found []*hcl.Attribute
+	if attr, ok := syntaxBody.Attributes[name]; ok {
+		found = append(found, attr.AsHCLAttribute())
+	}
+	for _, block := range syntaxBody.Blocks {
+		found = append(found, findAttributesByName(block.Body, name)...)
+	}
+	return found
+}
+
+// validateAgainstRule checks a single attribute value against rule's
+// pattern and length bounds, returning zero or more formatted error
+// messages (an attribute can fail both the pattern and a length bound).
+func validateAgainstRule(path string, attr *hcl.Attribute, rule PatternRule) []string {
+	lineNum := attr.Expr.Range().Start.Line
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.Type().FriendlyName() != "string" {
+		return []string{fmt.Sprintf("Error: [%s] Could not evaluate '%s' value in %s at line ~%d.", rule.Name, rule.Attribute, path, lineNum)}
+	}
+	strValue := value.AsString()
+
+	var messages []string
+	if !rule.compiled.MatchString(strValue) {
+		messages = append(messages, fmt.Sprintf("%s: [%s] Invalid '%s' value '%s' found in %s at line ~%d. Must match '%s'.", rule.Severity, rule.Name, rule.Attribute, strValue, path, lineNum, rule.Pattern))
+	}
+	if rule.MinLength > 0 && len(strValue) < rule.MinLength {
+		messages = append(messages, fmt.Sprintf("%s: [%s] '%s' value '%s' found in %s at line ~%d is shorter than the minimum length %d.", rule.Severity, rule.Name, rule.Attribute, strValue, path, lineNum, rule.MinLength))
+	}
+	if rule.MaxLength > 0 && len(strValue) > rule.MaxLength {
+		messages = append(messages, fmt.Sprintf("%s: [%s] '%s' value '%s' found in %s at line ~%d exceeds the maximum length %d.", rule.Severity, rule.Name, rule.Attribute, strValue, path, lineNum, rule.MaxLength))
+	}
+	return messages
+}
+
+// regexCmd is the "regx" subcommand: validateRegex driven by an external
+// or built-in pattern policy, analogous to genval's own regx command.
+var regexCmd = &cobra.Command{
+	Use:   "regx <directory>",
+	Short: "Validate Terraform attribute values against a pattern policy",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyRef, err := cmd.Flags().GetString("policy")
+		if err != nil {
+			return err
+		}
+		policy, err := LoadPolicy(policyRef)
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range validateRegex(args[0], policy) {
+			fmt.Fprintln(cmd.OutOrStdout(), msg)
+		}
+		return nil
+	},
+}
+
+func init() {
+	regexCmd.Flags().String("policy", "", "Pattern policy to apply: a file path, file://, http(s)://, or oci:// reference. Defaults to the built-in policy.")
+	rootCmd.AddCommand(regexCmd)
+}
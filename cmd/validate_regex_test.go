@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRegexFlagsInvalidAttributeValue(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "INVALID-UPPER-CASE"
+}
+`)
+
+	messages := validateRegex(dir, defaultPatternPolicy())
+
+	found := false
+	for _, msg := range messages {
+		if strings.Contains(msg, "AGF001") && strings.Contains(msg, "INVALID-UPPER-CASE") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("validateRegex(%s) = %v, want an AGF001 finding for the invalid posture_id", dir, messages)
+	}
+}
+
+func TestValidateRegexFindsNestedAttributes(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "my-posture"
+
+  policy_sets {
+    policy_set_id = "my-policy-set"
+
+    policies {
+      policy_id = "---"
+    }
+  }
+}
+`)
+
+	messages := validateRegex(dir, defaultPatternPolicy())
+
+	found := false
+	for _, msg := range messages {
+		if strings.Contains(msg, "AGF003") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("validateRegex(%s) = %v, want an AGF003 finding for the nested invalid policy_id", dir, messages)
+	}
+}
+
+func TestValidateRegexValidFileProducesNoMessages(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "my-posture"
+}
+`)
+
+	if messages := validateRegex(dir, defaultPatternPolicy()); len(messages) != 0 {
+		t.Errorf("validateRegex(%s) = %v, want no messages for a fully valid file", dir, messages)
+	}
+}
+
+func TestValidateRegexReportsUnsupportedJSONFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf.json", `{
+  "resource": {
+    "google_securityposture_posture": {
+      "example": {
+        "posture_id": "INVALID-UPPER-CASE"
+      }
+    }
+  }
+}`)
+
+	messages := validateRegex(dir, defaultPatternPolicy())
+
+	if len(messages) != 1 || !strings.Contains(messages[0], ".tf.json") {
+		t.Errorf("validateRegex(%s) = %v, want a single message reporting the .tf.json file as unsupported rather than silently producing zero findings", dir, messages)
+	}
+}
@@ -15,138 +15,377 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Regex for specific ID value validations. These describe the format the
+// Google provider itself enforces for the corresponding fields.
+var (
+	postureIDFormatValidation           = regexp.MustCompile(`^[a-z][a-z0-9-_]{0,62}$`)
+	policySetIDFormatValidation         = regexp.MustCompile(`^[a-z][a-z0-9-_]{0,62}$`)
+	policyIDFormatValidation            = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-_]{0,62}$`)
+	postureDeploymentIDFormatValidation = regexp.MustCompile(`^[a-z][a-z0-9-_]{0,62}$`)
+	parentFormatValidation              = regexp.MustCompile(`^(organizations/\d+|folders/\d+|projects/[^/]+)$`)
+)
+
+// resourceSchema matches top-level "resource" blocks so the walk can find
+// google_securityposture_posture declarations without needing to know
+// anything else about the rest of the file.
+var resourceSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+	},
+}
+
+// postureSchema describes the subset of a google_securityposture_posture
+// resource body that validation cares about: the posture_id attribute and
+// any number of nested policy_sets blocks.
+var postureSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "posture_id", Required: true},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "policy_sets"},
+	},
+}
+
+// policySetSchema describes a policy_sets block: a policy_set_id attribute
+// and any number of nested policies blocks.
+var policySetSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "policy_set_id", Required: true},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "policies"},
+	},
+}
+
+// policySchema describes a policies block: just the policy_id attribute.
+var policySchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "policy_id", Required: true},
+	},
+}
+
+// deploymentSchema describes the subset of a
+// google_securityposture_posture_deployment resource body validation
+// cares about: its own ID, the parent it deploys into, and the posture
+// (and, optionally, posture revision) it references.
+var deploymentSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "posture_deployment_id", Required: true},
+		{Name: "parent", Required: true},
+		{Name: "posture_id", Required: true},
+		{Name: "posture_revision_id", Required: false},
+	},
+}
+
+const (
+	posturePostureResourceType    = "google_securityposture_posture"
+	postureDeploymentResourceType = "google_securityposture_posture_deployment"
 )
 
-// getLineNumber searches for a string within a byte slice representing code
-// and returns the 1-based line number where the string is found.
-// It uses a regular expression for matching, making the search flexible.
-func getLineNumber(code []byte, searchString string) int {
-	scanner := bufio.NewScanner(strings.NewReader(string(code)))
-	lineNum := 1
-	// searchString is expected to be a valid regex pattern.
-	re, err := regexp.Compile(searchString)
+// validateTerraform scans a directory, parsing each .tf and .tf.json file
+// as HCL across opts.jobs() worker goroutines, and validates:
+// 1. Each google_securityposture_posture resource's posture_id, and every nested policy_set_id/policy_id.
+// 2. Each google_securityposture_posture_deployment resource's posture_deployment_id and parent.
+// 3. That a deployment's posture_id references a posture actually declared somewhere in terraformDir.
+// A file with more than one declaration of either resource type is rejected; a file with neither is skipped.
+// ctx cancels the scan; a cancelled or deadline-exceeded ctx makes
+// validateTerraform return early with whatever findings it already has.
+func validateTerraform(ctx context.Context, terraformDir string, opts WalkOptions) ([]Finding, error) {
+	declaredPostureIDs, err := collectDeclaredPostureIDs(ctx, terraformDir, opts)
 	if err != nil {
-		// If the search string itself is an invalid regex, return -1.
-		// This could indicate an issue with how searchString is constructed by the caller.
-		return -1
+		return nil, err
 	}
 
-	for scanner.Scan() {
-		if re.MatchString(scanner.Text()) {
-			return lineNum
-		}
-		lineNum++
+	return parallelWalkTerraformFiles(ctx, terraformDir, opts, func(path string) ([]Finding, error) {
+		return validateTerraformFile(path, declaredPostureIDs), nil
+	})
+}
+
+// validateTerraformFile parses a single file with its own hclparse.Parser
+// (parsers aren't safe to share across goroutines) and validates any
+// google_securityposture_posture / _deployment resources it declares.
+func validateTerraformFile(path string, declaredPostureIDs map[string]bool) []Finding {
+	isJSON := strings.HasSuffix(strings.ToLower(path), ".tf.json")
+
+	terraformCode, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return []Finding{{RuleID: RuleParseError, Severity: "ERROR", File: path, Line: 1, Message: fmt.Sprintf("Could not read Terraform file: %v", readErr)}}
 	}
-	return -1 // Return -1 if the searchString is not found
+
+	file, diags := parseTerraform(hclparse.NewParser(), path, terraformCode, isJSON)
+	if diags.HasErrors() {
+		return []Finding{{RuleID: RuleParseError, Severity: "ERROR", File: path, Line: 1, Message: fmt.Sprintf("Could not parse Terraform file: %s", diags.Error())}}
+	}
+
+	var findings []Finding
+	content, _, _ := file.Body.PartialContent(resourceSchema)
+	postureBlocks, deploymentBlocks := splitResourceBlocks(content.Blocks)
+
+	if len(postureBlocks) > 1 {
+		findings = append(findings, Finding{
+			RuleID:   RuleStructural,
+			Severity: "ERROR",
+			File:     path,
+			Line:     postureBlocks[0].DefRange.Start.Line,
+			Column:   postureBlocks[0].DefRange.Start.Column,
+			Message:  fmt.Sprintf("File must contain at most one '%s' resource declaration. Found %d.", posturePostureResourceType, len(postureBlocks)),
+		})
+	} else if len(postureBlocks) == 1 {
+		findings = append(findings, validatePostureBlock(path, postureBlocks[0])...)
+	}
+
+	if len(deploymentBlocks) > 1 {
+		findings = append(findings, Finding{
+			RuleID:   RuleStructural,
+			Severity: "ERROR",
+			File:     path,
+			Line:     deploymentBlocks[0].DefRange.Start.Line,
+			Column:   deploymentBlocks[0].DefRange.Start.Column,
+			Message:  fmt.Sprintf("File must contain at most one '%s' resource declaration. Found %d.", postureDeploymentResourceType, len(deploymentBlocks)),
+		})
+	} else if len(deploymentBlocks) == 1 {
+		findings = append(findings, validateDeploymentBlock(path, deploymentBlocks[0], declaredPostureIDs)...)
+	}
+
+	return findings
 }
 
-// validateTerraform walks through a directory, reads .tf files, and validates:
-// 1. Each file contains exactly one 'google_securityposture_posture' resource declaration.
-// 2. If so, it validates all found 'posture_id' values in the file.
-// 3. It validates all found 'policy_set_id' values in the file.
-// 4. It validates all found 'policy_id' values in the file.
-// These ID validations are performed on any matching assignment pattern, regardless of HCL structure.
-func validateTerraform(terraformDir string) []string {
-	var errorMessages []string
-
-	// Regex for specific ID value validations
-	postureIDFormatValidation := regexp.MustCompile(`^[a-z][a-z0-9-_]{0,62}$`)
-	policySetIDFormatValidation := regexp.MustCompile(`^[a-z][a-z0-9-_]{0,62}$`)
-	policyIDFormatValidation := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-_]{0,62}$`)
-
-	// Regex to extract ID values from assignments anywhere in the file
-	postureIDExtractRegex := regexp.MustCompile(`posture_id\s*=\s*"(.*?)"`)
-	policySetIDExtractRegex := regexp.MustCompile(`policy_set_id\s*=\s*"(.*?)"`)
-	policyIDExtractRegex := regexp.MustCompile(`policy_id\s*=\s*"(.*?)"`)
-
-	err := filepath.Walk(terraformDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err // Propagate error to stop Walk if critical (e.g., dir not found)
-		}
-		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".tf") {
-			return nil // Skip directories and non-.tf files.
+// parseTerraform parses a single file's bytes as either native HCL or
+// HCL-in-JSON syntax, depending on isJSON.
+func parseTerraform(parser *hclparse.Parser, path string, code []byte, isJSON bool) (*hcl.File, hcl.Diagnostics) {
+	if isJSON {
+		return parser.ParseJSON(code, path)
+	}
+	return parser.ParseHCL(code, path)
+}
+
+// splitResourceBlocks separates the blocks PartialContent(resourceSchema)
+// returned into the two resource types validateTerraform understands.
+func splitResourceBlocks(blocks hcl.Blocks) (postureBlocks, deploymentBlocks []*hcl.Block) {
+	for _, block := range blocks {
+		switch block.Labels[0] {
+		case posturePostureResourceType:
+			postureBlocks = append(postureBlocks, block)
+		case postureDeploymentResourceType:
+			deploymentBlocks = append(deploymentBlocks, block)
 		}
+	}
+	return postureBlocks, deploymentBlocks
+}
+
+// collectDeclaredPostureIDs performs a first pass over terraformDir,
+// gathering every posture_id assigned to a google_securityposture_posture
+// resource so the second pass can cross-check deployment references
+// against postures declared anywhere in the directory, not just the same
+// file. It reuses the same opts.jobs() worker pool as the main validation
+// walk, each worker parsing its own files with its own hclparse.Parser and
+// merging into declared under a mutex, so this pass scales the same way
+// the validation pass does instead of bottlenecking it behind a single
+// sequential walk. Parse/read errors are silently skipped; validateTerraform's
+// own pass over the same files reports them.
+func collectDeclaredPostureIDs(ctx context.Context, terraformDir string, opts WalkOptions) (map[string]bool, error) {
+	var mu sync.Mutex
+	declared := map[string]bool{}
 
-		terraformCode, readErr := os.ReadFile(path)
+	err := walkTerraformFiles(ctx, terraformDir, opts, func(path string) error {
+		isJSON := strings.HasSuffix(strings.ToLower(path), ".tf.json")
+
+		code, readErr := os.ReadFile(path)
 		if readErr != nil {
-			errorMessages = append(errorMessages, fmt.Sprintf("Error: Could not read Terraform file: %s. Details: %v", path, readErr))
-			return nil // Continue with the next file.
+			return nil
+		}
+		file, diags := parseTerraform(hclparse.NewParser(), path, code, isJSON)
+		if diags.HasErrors() {
+			return nil
 		}
 
-		// 1. Validate there's only one google_securityposture_posture resource declaration
-		resourceDeclarationRegex := regexp.MustCompile(`resource "google_securityposture_posture" "([^"]*)"\s*{`)
-		resourceDeclarationMatches := resourceDeclarationRegex.FindAllStringIndex(string(terraformCode), -1)
-
-		if len(resourceDeclarationMatches) != 1 {
-			var lineNum int
-			if len(resourceDeclarationMatches) > 0 {
-				// Get line number of the first declaration found
-				firstMatchStartOffset := resourceDeclarationMatches[0][0]
-				lineNum = getLineNumber(terraformCode[:firstMatchStartOffset+1], `resource "google_securityposture_posture"`) // Search up to the match
-				if lineNum == -1 {                                                                                            // Fallback if specific line not found
-					lineNum = getLineNumber(terraformCode, `resource "google_securityposture_posture"`)
-				}
-			} else {
-				lineNum = 1 // Default to start of file if no resource found.
+		content, _, _ := file.Body.PartialContent(resourceSchema)
+		for _, block := range content.Blocks {
+			if block.Labels[0] != posturePostureResourceType {
+				continue
+			}
+			postureContent, _, _ := block.Body.PartialContent(postureSchema)
+			attr, ok := postureContent.Attributes["posture_id"]
+			if !ok {
+				continue
+			}
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() || value.Type() != cty.String {
+				continue
 			}
-			errorMessages = append(errorMessages, fmt.Sprintf("Error: File %s must contain exactly one 'google_securityposture_posture' resource declaration. Found %d. First occurrence (if any) near line ~%d.", path, len(resourceDeclarationMatches), lineNum))
-			return nil // Stop processing this file if resource count is not 1.
+
+			mu.Lock()
+			declared[value.AsString()] = true
+			mu.Unlock()
 		}
+		return nil
+	})
+
+	return declared, err
+}
 
-		// If exactly one resource declaration is found, proceed to validate IDs found anywhere in the file.
+// validatePostureBlock validates the posture_id of a single
+// google_securityposture_posture resource, along with every policy_set_id
+// and policy_id nested inside it.
+func validatePostureBlock(path string, resource *hcl.Block) []Finding {
+	var findings []Finding
 
-		// 2. Validate all 'posture_id' values found in the file
-		allPostureIDAssignments := postureIDExtractRegex.FindAllStringSubmatch(string(terraformCode), -1)
-		if len(allPostureIDAssignments) == 0 {
-			// This could be an error if a posture_id is strictly expected within the declared resource,
-			// but per simplified rules, we only validate what we find.
-			// If the resource is declared, it *should* have a posture_id.
-			// Let's add an error if the resource is declared but no posture_id assignment is found in the file.
-			errorMessages = append(errorMessages, fmt.Sprintf("Error: 'google_securityposture_posture' resource declared in %s, but no 'posture_id' assignment found in the file.", path))
+	postureContent, _, _ := resource.Body.PartialContent(postureSchema)
+
+	postureIDAttr, ok := postureContent.Attributes["posture_id"]
+	if !ok {
+		findings = append(findings, missingAttributeFinding(path, resource, posturePostureResourceType, "posture_id"))
+	} else if finding, ok := validateIDAttribute(path, postureIDAttr, postureIDFormatValidation, "posture_id", RuleInvalidPostureID); !ok {
+		findings = append(findings, finding)
+	}
+
+	for _, policySetBlock := range postureContent.Blocks {
+		policySetContent, _, _ := policySetBlock.Body.PartialContent(policySetSchema)
+		if attr, ok := policySetContent.Attributes["policy_set_id"]; ok {
+			if finding, ok := validateIDAttribute(path, attr, policySetIDFormatValidation, "policy_set_id", RuleInvalidPolicySetID); !ok {
+				findings = append(findings, finding)
+			}
 		}
-		for _, match := range allPostureIDAssignments {
-			postureIDValue := match[1] // The captured group (the value)
-			if !postureIDFormatValidation.MatchString(postureIDValue) {
-				// For line number, search for the specific assignment `posture_id = "value"`
-				searchPattern := fmt.Sprintf(`posture_id\s*=\s*"%s"`, regexp.QuoteMeta(postureIDValue))
-				lineNum := getLineNumber(terraformCode, searchPattern)
-				errorMessages = append(errorMessages, fmt.Sprintf("Error: Invalid 'posture_id' value '%s' found in %s at line ~%d. Must match '%s'.", postureIDValue, path, lineNum, postureIDFormatValidation.String()))
+		for _, policyBlock := range policySetContent.Blocks {
+			policyContent, _, _ := policyBlock.Body.PartialContent(policySchema)
+			if attr, ok := policyContent.Attributes["policy_id"]; ok {
+				if finding, ok := validateIDAttribute(path, attr, policyIDFormatValidation, "policy_id", RuleInvalidPolicyID); !ok {
+					findings = append(findings, finding)
+				}
 			}
 		}
+	}
 
-		// 3. Validate all 'policy_set_id' values found in the file
-		allPolicySetIDAssignments := policySetIDExtractRegex.FindAllStringSubmatch(string(terraformCode), -1)
-		for _, match := range allPolicySetIDAssignments {
-			policySetIDValue := match[1]
-			if !policySetIDFormatValidation.MatchString(policySetIDValue) {
-				searchPattern := fmt.Sprintf(`policy_set_id\s*=\s*"%s"`, regexp.QuoteMeta(policySetIDValue))
-				lineNum := getLineNumber(terraformCode, searchPattern)
-				errorMessages = append(errorMessages, fmt.Sprintf("Error: Invalid 'policy_set_id' value '%s' found in %s at line ~%d. Must match '%s'.", policySetIDValue, path, lineNum, policySetIDFormatValidation.String()))
-			}
+	return findings
+}
+
+// validateDeploymentBlock validates a single
+// google_securityposture_posture_deployment resource: its own ID, its
+// parent, and that the posture it references actually exists somewhere
+// in the scanned directory. posture_revision_id isn't cross-checked: it
+// names a revision of the posture, not a posture_id, and this tree has
+// nowhere else that declares or collects revision identifiers to check
+// it against.
+func validateDeploymentBlock(path string, resource *hcl.Block, declaredPostureIDs map[string]bool) []Finding {
+	var findings []Finding
+
+	deploymentContent, _, _ := resource.Body.PartialContent(deploymentSchema)
+
+	if attr, ok := deploymentContent.Attributes["posture_deployment_id"]; ok {
+		if finding, ok := validateIDAttribute(path, attr, postureDeploymentIDFormatValidation, "posture_deployment_id", RuleInvalidPostureDeploymentID); !ok {
+			findings = append(findings, finding)
 		}
+	} else {
+		findings = append(findings, missingAttributeFinding(path, resource, postureDeploymentResourceType, "posture_deployment_id"))
+	}
 
-		// 4. Validate all 'policy_id' values found in the file
-		allPolicyIDAssignments := policyIDExtractRegex.FindAllStringSubmatch(string(terraformCode), -1)
-		for _, match := range allPolicyIDAssignments {
-			policyIDValue := match[1]
-			if !policyIDFormatValidation.MatchString(policyIDValue) {
-				searchPattern := fmt.Sprintf(`policy_id\s*=\s*"%s"`, regexp.QuoteMeta(policyIDValue))
-				lineNum := getLineNumber(terraformCode, searchPattern)
-				errorMessages = append(errorMessages, fmt.Sprintf("Error: Invalid 'policy_id' value '%s' found in %s at line ~%d. Must match '%s'.", policyIDValue, path, lineNum, policyIDFormatValidation.String()))
-			}
+	if attr, ok := deploymentContent.Attributes["parent"]; ok {
+		if finding, ok := validateIDAttribute(path, attr, parentFormatValidation, "parent", RuleInvalidParent); !ok {
+			findings = append(findings, finding)
 		}
-		return nil
-	})
+	} else {
+		findings = append(findings, missingAttributeFinding(path, resource, postureDeploymentResourceType, "parent"))
+	}
 
-	if err != nil {
-		errorMessages = append(errorMessages, fmt.Sprintf("Error: Could not walk the directory: %v", err))
+	if attr, ok := deploymentContent.Attributes["posture_id"]; ok {
+		if finding, ok := validatePostureReference(path, attr, declaredPostureIDs); !ok {
+			findings = append(findings, finding)
+		}
+	} else {
+		findings = append(findings, missingAttributeFinding(path, resource, postureDeploymentResourceType, "posture_id"))
+	}
+
+	return findings
+}
+
+// missingAttributeFinding reports a required attribute absent from a
+// resource block, the same way validatePostureBlock does for a missing
+// posture_id.
+func missingAttributeFinding(path string, resource *hcl.Block, resourceType, attrName string) Finding {
+	return Finding{
+		RuleID:   RuleStructural,
+		Severity: "ERROR",
+		File:     path,
+		Line:     resource.DefRange.Start.Line,
+		Column:   resource.DefRange.Start.Column,
+		Message:  fmt.Sprintf("'%s' resource declared, but no '%s' assignment found in the file.", resourceType, attrName),
 	}
+}
 
-	return errorMessages
+// validatePostureReference checks that attr's value names a posture_id
+// collected from somewhere in the scanned directory. Unlike a format
+// violation this is its own rule (RuleDanglingPostureReference), so CI
+// can gate deployments on unresolved posture links independently of
+// formatting rules.
+func validatePostureReference(path string, attr *hcl.Attribute, declaredPostureIDs map[string]bool) (Finding, bool) {
+	start := attr.Expr.Range().Start
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String {
+		return Finding{}, true // Not a literal reference (e.g. an interpolated expression); nothing to cross-check.
+	}
+
+	referenced := value.AsString()
+	if !declaredPostureIDs[referenced] {
+		return Finding{
+			RuleID:   RuleDanglingPostureReference,
+			Severity: "ERROR",
+			File:     path,
+			Line:     start.Line,
+			Column:   start.Column,
+			Message:  fmt.Sprintf("References posture_id '%s', but no '%s' with that posture_id was found in the scanned directory.", referenced, posturePostureResourceType),
+			Expected: "an existing posture_id",
+			Actual:   referenced,
+		}, false
+	}
+	return Finding{}, true
+}
+
+// validateIDAttribute evaluates an HCL attribute as a literal string and
+// checks it against format. It returns the Finding and false when the
+// value doesn't match; ok is true when there's nothing to report. The
+// line number comes straight from the attribute's hcl.Range, so it's
+// exact rather than a second regex-based scan of the source.
+//
+// An attribute that can't be evaluated without a Terraform graph (a
+// var./local. reference, interpolation, a function call) is reported ok:
+// not being able to statically resolve a value isn't evidence the value
+// is wrong, and most real-world modules parameterize these IDs, so
+// flagging it as invalid would make that the common case, not the edge
+// case. validatePostureReference treats an unevaluable posture_id
+// reference the same way.
+func validateIDAttribute(path string, attr *hcl.Attribute, format *regexp.Regexp, attrName, ruleID string) (Finding, bool) {
+	start := attr.Expr.Range().Start
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String {
+		return Finding{}, true
+	}
+
+	strValue := value.AsString()
+	if !format.MatchString(strValue) {
+		return Finding{
+			RuleID:   ruleID,
+			Severity: "ERROR",
+			File:     path,
+			Line:     start.Line,
+			Column:   start.Column,
+			Message:  fmt.Sprintf("Invalid '%s' value '%s'. Must match '%s'.", attrName, strValue, format.String()),
+			Expected: format.String(),
+			Actual:   strValue,
+		}, false
+	}
+	return Finding{}, true
 }
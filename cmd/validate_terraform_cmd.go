@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// renderProgress prints a single-line, carriage-return-overwritten
+// counter of files scanned and findings found so far.
+func renderProgress(cmd *cobra.Command) func(filesScanned, findingsFound int) {
+	return func(filesScanned, findingsFound int) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "\rscanned %d file(s), %d finding(s)", filesScanned, findingsFound)
+	}
+}
+
+// tfCmd is the "tf" subcommand wrapping validateTerraform, with an
+// optional remediation pass for violations that can be fixed
+// mechanically.
+var tfCmd = &cobra.Command{
+	Use:   "tf <directory>",
+	Short: "Validate google_securityposture_posture Terraform resources",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, err := cmd.Flags().GetBool("fix")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		if fix || dryRun {
+			summary, err := remediateTerraform(args[0], dryRun)
+			if err != nil {
+				return err
+			}
+			printRemediationSummary(cmd, summary, dryRun)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		outputFile, err := cmd.Flags().GetString("output-file")
+		if err != nil {
+			return err
+		}
+		jobs, err := cmd.Flags().GetInt("jobs")
+		if err != nil {
+			return err
+		}
+		progress, err := cmd.Flags().GetBool("progress")
+		if err != nil {
+			return err
+		}
+
+		reporter, err := NewReporter(output)
+		if err != nil {
+			return err
+		}
+
+		opts := WalkOptions{Jobs: jobs}
+		if progress {
+			opts.Progress = renderProgress(cmd)
+		}
+
+		findings, err := validateTerraform(cmd.Context(), args[0], opts)
+		if progress {
+			fmt.Fprintln(cmd.ErrOrStderr())
+		}
+		if err != nil {
+			return err
+		}
+
+		report, err := reporter.Report(findings)
+		if err != nil {
+			return err
+		}
+
+		if outputFile == "" {
+			_, err = cmd.OutOrStdout().Write(report)
+			return err
+		}
+		return os.WriteFile(outputFile, report, 0o644)
+	},
+}
+
+// printRemediationSummary renders a diff-style summary of the fixes
+// remediateTerraform applied (or would apply, under --dry-run).
+func printRemediationSummary(cmd *cobra.Command, summary *RemediationSummary, dryRun bool) {
+	verb := "Fixed"
+	if dryRun {
+		verb = "Would fix"
+	}
+	for _, fix := range summary.Fixes {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s:%d: %s = %q -> %q\n", verb, fix.Path, fix.Line, fix.Attribute, fix.Before, fix.After)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Scanned %d file(s), fixed %d, skipped %d, %d still failing.\n",
+		summary.FilesScanned, summary.FilesFixed, summary.FilesSkipped, summary.FilesStillFailing)
+}
+
+func init() {
+	tfCmd.Flags().Bool("fix", false, "Rewrite invalid posture_id/policy_set_id/policy_id values in place where they can be mechanically normalized.")
+	tfCmd.Flags().Bool("dry-run", false, "Print the fixes --fix would apply without writing any files.")
+	tfCmd.Flags().String("output", "text", "Output format: text, json, or sarif.")
+	tfCmd.Flags().String("output-file", "", "Write the report to this path instead of stdout.")
+	tfCmd.Flags().Int("jobs", 0, "Number of files to scan concurrently. Defaults to runtime.NumCPU().")
+	tfCmd.Flags().Bool("progress", false, "Print a live counter of files scanned and findings found.")
+	rootCmd.AddCommand(tfCmd)
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTerraformFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func findingRuleIDs(findings []Finding) []string {
+	ruleIDs := make([]string, len(findings))
+	for i, finding := range findings {
+		ruleIDs[i] = finding.RuleID
+	}
+	return ruleIDs
+}
+
+func containsRule(findings []Finding, ruleID string) bool {
+	for _, finding := range findings {
+		if finding.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateTerraformFileSkipsUnevaluableIDAttributes(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "${var.prefix}-posture"
+
+  policy_sets {
+    policy_set_id = "${var.prefix}-policy-set"
+
+    policies {
+      policy_id = "${var.prefix}-policy"
+    }
+  }
+}
+`)
+
+	findings := validateTerraformFile(path, map[string]bool{})
+
+	if len(findings) != 0 {
+		t.Errorf("validateTerraformFile(%s) = %v, want no findings: an interpolated posture_id/policy_set_id/policy_id can't be statically evaluated and isn't thereby invalid", path, findingRuleIDs(findings))
+	}
+}
+
+func TestValidateTerraformFileSkipsUnevaluableDeploymentAttributes(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTerraformFile(t, dir, "deployment.tf", `
+resource "google_securityposture_posture_deployment" "example" {
+  posture_deployment_id = "${var.prefix}-deployment"
+  parent                = var.parent
+  posture_id            = "my-posture"
+}
+`)
+
+	findings := validateTerraformFile(path, map[string]bool{"my-posture": true})
+
+	if containsRule(findings, RuleInvalidPostureDeploymentID) || containsRule(findings, RuleInvalidParent) {
+		t.Errorf("validateTerraformFile(%s) = %v, want no AGF004/AGF005 findings for unevaluable posture_deployment_id/parent expressions", path, findingRuleIDs(findings))
+	}
+}
+
+func TestValidateTerraformFileDeploymentMissingPostureID(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTerraformFile(t, dir, "deployment.tf", `
+resource "google_securityposture_posture_deployment" "example" {
+  posture_deployment_id = "my-deployment"
+  parent                = "organizations/123"
+}
+`)
+
+	findings := validateTerraformFile(path, map[string]bool{})
+
+	if !containsRule(findings, RuleStructural) {
+		t.Errorf("validateTerraformFile(%s) = %v, want a %s finding for the missing posture_id", path, findingRuleIDs(findings), RuleStructural)
+	}
+}
+
+func TestValidateTerraformFileDeploymentRevisionIDOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeTerraformFile(t, dir, "posture.tf", `
+resource "google_securityposture_posture" "example" {
+  posture_id = "my-posture"
+}
+`)
+	path := writeTerraformFile(t, dir, "deployment.tf", `
+resource "google_securityposture_posture_deployment" "example" {
+  posture_deployment_id = "my-deployment"
+  parent                = "organizations/123"
+  posture_id            = "my-posture"
+  posture_revision_id   = "rev-does-not-match-any-posture-id"
+}
+`)
+
+	declared, err := collectDeclaredPostureIDs(context.Background(), dir, WalkOptions{})
+	if err != nil {
+		t.Fatalf("collectDeclaredPostureIDs(%s) = %v", dir, err)
+	}
+
+	findings := validateTerraformFile(path, declared)
+
+	if containsRule(findings, RuleDanglingPostureReference) {
+		t.Errorf("validateTerraformFile(%s) = %v, want no %s finding: posture_revision_id isn't a posture_id and shouldn't be cross-checked against declaredPostureIDs", path, findingRuleIDs(findings), RuleDanglingPostureReference)
+	}
+}
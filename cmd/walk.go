@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// WalkOptions configures parallelWalkTerraformFiles.
+type WalkOptions struct {
+	// Jobs is the number of worker goroutines scanning files concurrently.
+	// Zero means runtime.NumCPU().
+	Jobs int
+	// Progress, when non-nil, is called after each file finishes scanning
+	// with the running totals of files scanned and findings produced so
+	// far, so a caller can render a live counter.
+	Progress func(filesScanned, findingsFound int)
+}
+
+func (o WalkOptions) jobs() int {
+	if o.Jobs > 0 {
+		return o.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// terraformFiles lists every .tf/.tf.json file under dir, in the order
+// filepath.Walk visits them. Listing the set of files up front lets the
+// worker pool below fan out over a known, bounded list instead of racing
+// filepath.Walk's own single-threaded callback.
+func terraformFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		lower := strings.ToLower(info.Name())
+		if strings.HasSuffix(lower, ".tf.json") || strings.HasSuffix(lower, ".tf") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// walkTerraformFiles feeds every Terraform file under dir to fn across
+// opts.jobs() worker goroutines. It stops feeding new files and returns
+// as soon as ctx is cancelled or fn returns an error, so it's safe to
+// call from a long-running service that needs to bound how long a scan
+// can run. Callers that need to accumulate a result across files do so
+// inside fn, guarded by their own mutex, since the result shape (findings,
+// a set of declared IDs, ...) varies by caller.
+func walkTerraformFiles(ctx context.Context, dir string, opts WalkOptions, fn func(path string) error) error {
+	paths, err := terraformFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	pathCh := make(chan string)
+
+	g.Go(func() error {
+		defer close(pathCh)
+		for _, path := range paths {
+			select {
+			case pathCh <- path:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	for i := 0; i < opts.jobs(); i++ {
+		g.Go(func() error {
+			for path := range pathCh {
+				if err := fn(path); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// parallelWalkTerraformFiles runs fn once per Terraform file under dir
+// across opts.jobs() worker goroutines, collecting their findings behind
+// a mutex rather than appending to a shared slice directly. The scanned
+// count and the Progress call happen under that same mutex so the last
+// update a caller observes is always the final, highest count: calling
+// Progress outside the lock (e.g. via a separate atomic counter) would let
+// a preempted goroutine deliver a stale count after a newer one already
+// fired.
+func parallelWalkTerraformFiles(ctx context.Context, dir string, opts WalkOptions, fn func(path string) ([]Finding, error)) ([]Finding, error) {
+	var (
+		mu       sync.Mutex
+		findings []Finding
+		scanned  int
+	)
+
+	err := walkTerraformFiles(ctx, dir, opts, func(path string) error {
+		fileFindings, err := fn(path)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		findings = append(findings, fileFindings...)
+		scanned++
+		total, count := len(findings), scanned
+		if opts.Progress != nil {
+			opts.Progress(count, total)
+		}
+		mu.Unlock()
+
+		return nil
+	})
+	return findings, err
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func writeNumberedTerraformFiles(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		writeTerraformFile(t, dir, fmt.Sprintf("posture%d.tf", i), fmt.Sprintf(`
+resource "google_securityposture_posture" "example" {
+  posture_id = "posture-%d"
+}
+`, i))
+	}
+	return dir
+}
+
+func TestParallelWalkTerraformFilesVisitsEveryFileAcrossWorkers(t *testing.T) {
+	const fileCount = 20
+	dir := writeNumberedTerraformFiles(t, fileCount)
+
+	var visited int64
+	findings, err := parallelWalkTerraformFiles(context.Background(), dir, WalkOptions{Jobs: 4}, func(path string) ([]Finding, error) {
+		atomic.AddInt64(&visited, 1)
+		return []Finding{{RuleID: RuleStructural, File: path}}, nil
+	})
+	if err != nil {
+		t.Fatalf("parallelWalkTerraformFiles(%s) = %v", dir, err)
+	}
+
+	if int(visited) != fileCount {
+		t.Errorf("parallelWalkTerraformFiles(%s) visited %d files, want %d: jobs>1 must still cover every file exactly once", dir, visited, fileCount)
+	}
+	if len(findings) != fileCount {
+		t.Errorf("parallelWalkTerraformFiles(%s) returned %d findings, want %d", dir, len(findings), fileCount)
+	}
+}
+
+func TestParallelWalkTerraformFilesReportsProgress(t *testing.T) {
+	const fileCount = 10
+	dir := writeNumberedTerraformFiles(t, fileCount)
+
+	var mu sync.Mutex
+	var lastScanned, lastFindings int
+	calls := 0
+
+	_, err := parallelWalkTerraformFiles(context.Background(), dir, WalkOptions{
+		Jobs: 3,
+		Progress: func(filesScanned, findingsFound int) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			lastScanned, lastFindings = filesScanned, findingsFound
+		},
+	}, func(path string) ([]Finding, error) {
+		return []Finding{{RuleID: RuleStructural, File: path}}, nil
+	})
+	if err != nil {
+		t.Fatalf("parallelWalkTerraformFiles(%s) = %v", dir, err)
+	}
+
+	if calls != fileCount {
+		t.Errorf("Progress callback ran %d times, want %d (once per file)", calls, fileCount)
+	}
+	if lastScanned != fileCount || lastFindings != fileCount {
+		t.Errorf("Progress callback's final counts = (%d, %d), want (%d, %d)", lastScanned, lastFindings, fileCount, fileCount)
+	}
+}
+
+func TestParallelWalkTerraformFilesStopsOnCancellation(t *testing.T) {
+	const fileCount = 50
+	dir := writeNumberedTerraformFiles(t, fileCount)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var processed int64
+	_, err := parallelWalkTerraformFiles(ctx, dir, WalkOptions{Jobs: 2}, func(path string) ([]Finding, error) {
+		if atomic.AddInt64(&processed, 1) == 1 {
+			cancel()
+		}
+		return nil, nil
+	})
+
+	if err == nil {
+		t.Fatalf("parallelWalkTerraformFiles(%s) = nil error, want a cancellation error once ctx is cancelled mid-scan", dir)
+	}
+	if got := int(atomic.LoadInt64(&processed)); got >= fileCount {
+		t.Errorf("parallelWalkTerraformFiles(%s) processed all %d files despite cancellation after the first; got %d", dir, fileCount, got)
+	}
+}
+
+func TestParallelWalkTerraformFilesPropagatesWorkerError(t *testing.T) {
+	dir := writeNumberedTerraformFiles(t, 5)
+	wantErr := fmt.Errorf("boom")
+
+	_, err := parallelWalkTerraformFiles(context.Background(), dir, WalkOptions{Jobs: 2}, func(path string) ([]Finding, error) {
+		return nil, wantErr
+	})
+
+	if err == nil {
+		t.Fatalf("parallelWalkTerraformFiles(%s) = nil error, want the worker's error to propagate", dir)
+	}
+}